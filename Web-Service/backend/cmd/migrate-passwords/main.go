@@ -0,0 +1,51 @@
+// Command migrate-passwords rehashes any plaintext User.Password values to
+// bcrypt on first boot after the auth rework. It is idempotent: a password
+// that already looks like a bcrypt hash is left untouched.
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/handpose/webservice/internal/auth"
+	"github.com/handpose/webservice/internal/config"
+	"github.com/handpose/webservice/internal/models"
+)
+
+const bcryptPrefix = "$2"
+
+func main() {
+	cfg := config.Load()
+
+	db, err := gorm.Open(sqlite.Open(cfg.DBPath), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+
+	var users []models.User
+	if err := db.Find(&users).Error; err != nil {
+		log.Fatalf("failed to load users: %v", err)
+	}
+
+	migrated := 0
+	for _, user := range users {
+		if strings.HasPrefix(user.Password, bcryptPrefix) {
+			continue
+		}
+
+		hashed, err := auth.HashPassword(user.Password)
+		if err != nil {
+			log.Fatalf("failed to hash password for user %d: %v", user.ID, err)
+		}
+
+		if err := db.Model(&models.User{}).Where("id = ?", user.ID).Update("password", hashed).Error; err != nil {
+			log.Fatalf("failed to update password for user %d: %v", user.ID, err)
+		}
+		migrated++
+	}
+
+	log.Printf("Rehashed %d plaintext password(s) out of %d user(s)", migrated, len(users))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/handpose/webservice/internal/auth"
+	"github.com/handpose/webservice/internal/config"
+	"github.com/handpose/webservice/internal/models"
+	"github.com/handpose/webservice/internal/queue"
+	"github.com/handpose/webservice/internal/server"
+	"github.com/handpose/webservice/internal/storage"
+)
+
+var startTime = time.Now()
+
+func main() {
+	cfg := config.Load()
+
+	db, err := gorm.Open(sqlite.Open(cfg.DBPath), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+	// Migrate all models
+	db.AutoMigrate(&models.User{}, &models.Project{}, &models.Patient{}, &models.Recording{})
+
+	// Seed Admin
+	var count int64
+	db.Model(&models.User{}).Count(&count)
+	if count == 0 {
+		hashed, err := auth.HashPassword("admin")
+		if err != nil {
+			log.Fatalf("failed to hash seed admin password: %v", err)
+		}
+		db.Create(&models.User{
+			Email:      "admin@handpose.com",
+			Password:   hashed,
+			FullName:   "System Admin",
+			IsApproved: true,
+			IsAdmin:    true,
+		})
+	}
+
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize storage backend: %v", err)
+	}
+
+	queueClient := queue.NewClient(cfg)
+	defer queueClient.Close()
+
+	srv := server.New(db, cfg, store, queueClient)
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+	// Simple logger
+	r.Use(func(c *gin.Context) {
+		log.Printf("%s %s", c.Request.Method, c.Request.URL.Path)
+		c.Next()
+	})
+
+	// Buffer up to 100MB of multipart form data in memory; larger parts
+	// spill to temporary files rather than being rejected.
+	r.MaxMultipartMemory = 100 << 20
+
+	requireAuth := auth.Middleware(db, cfg.JWTSecret)
+
+	api := r.Group("/api")
+	{
+		api.POST("/login", srv.Login)
+		api.POST("/register", srv.Register)
+		api.GET("/health", func(c *gin.Context) {
+			c.JSON(200, gin.H{"status": "ok"})
+		})
+
+		// Projects
+		projects := api.Group("/projects", requireAuth)
+		projects.GET("", srv.ListProjects)
+		projects.POST("", srv.CreateProject)
+
+		// Patients
+		patients := api.Group("/patients", requireAuth)
+		patients.GET("", srv.ListPatients)
+		patients.POST("", srv.CreatePatient)
+
+		// Recordings
+		recordings := api.Group("/patients/:id/recordings", requireAuth)
+		recordings.GET("", srv.ListRecordings)
+		recordings.POST("", srv.UploadRecording)
+		api.GET("/recordings/:id/video", requireAuth, srv.GetRecordingVideo)
+		api.GET("/recordings/:id/status", requireAuth, srv.GetRecordingStatus)
+		api.POST("/recordings/:id/export/fhir", requireAuth, srv.ExportFHIR)
+		api.POST("/recordings/:id/export/dicom", requireAuth, srv.ExportDICOM)
+
+		// Admin
+		admin := api.Group("/admin", requireAuth, auth.AdminOnly())
+		admin.POST("/users/:id/approve", srv.ApproveUser)
+		admin.POST("/recordings/:id/rejudge", srv.RejudgeRecording)
+	}
+
+	staticPath := "../frontend/dist"
+	r.Static("/assets", filepath.Join(staticPath, "assets"))
+
+	// GetRecordingVideo's redirect target for the local backend is a
+	// SignedURL under /data; cloud backends never hit this route. The
+	// handler itself verifies the signature and expiry, so the mount is
+	// safe to expose unauthenticated.
+	if lfs, ok := store.(*storage.LocalFS); ok {
+		r.Any("/data/*filepath", gin.WrapH(lfs.Handler()))
+	}
+
+	r.NoRoute(func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if strings.HasPrefix(path, "/api") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API route not found"})
+			return
+		}
+		c.File(filepath.Join(staticPath, "index.html"))
+	})
+
+	log.Printf("Starting Web-Service on http://localhost:%s", cfg.Port)
+	if err := r.Run(":" + cfg.Port); err != nil {
+		log.Fatalf("Failed to run server: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Command worker consumes recording:process tasks from the asynq queue,
+// computes derived keypoint metrics, and advances each Recording through
+// "processing" to "completed" (or "failed" with an error recorded).
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+
+	"github.com/handpose/webservice/internal/analysis"
+	"github.com/handpose/webservice/internal/config"
+	"github.com/handpose/webservice/internal/metrics"
+	"github.com/handpose/webservice/internal/models"
+	"github.com/handpose/webservice/internal/queue"
+	"github.com/handpose/webservice/internal/storage"
+)
+
+type processor struct {
+	db    *gorm.DB
+	store storage.Storage
+}
+
+func (p *processor) ProcessTask(ctx context.Context, t *asynq.Task) error {
+	var payload queue.ProcessRecordingPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	start := time.Now()
+	err := p.process(ctx, payload.RecordingID)
+
+	outcome := "completed"
+	if err != nil {
+		outcome = "failed"
+	}
+	metrics.ProcessingDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+func (p *processor) process(ctx context.Context, recordingID uint) error {
+	var recording models.Recording
+	if err := p.db.First(&recording, recordingID).Error; err != nil {
+		return fmt.Errorf("load recording %d: %w", recordingID, err)
+	}
+
+	recording.Status = "processing"
+	p.db.Save(&recording)
+
+	if err := p.runAnalysis(ctx, &recording); err != nil {
+		recording.Status = "failed"
+		recording.Error = err.Error()
+		p.db.Save(&recording)
+		return err
+	}
+
+	recording.Status = "completed"
+	recording.Error = ""
+	p.db.Save(&recording)
+	return nil
+}
+
+func (p *processor) runAnalysis(ctx context.Context, recording *models.Recording) error {
+	keypointsKey := storage.KeyFromURL(recording.KeypointsPath)
+
+	keypointsReader, err := p.store.Get(ctx, keypointsKey)
+	if err != nil {
+		return fmt.Errorf("fetch keypoints: %w", err)
+	}
+	defer keypointsReader.Close()
+
+	keypoints, err := io.ReadAll(keypointsReader)
+	if err != nil {
+		return fmt.Errorf("read keypoints: %w", err)
+	}
+
+	result, err := analysis.Compute(keypoints)
+	if err != nil {
+		return fmt.Errorf("compute analysis: %w", err)
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal analysis: %w", err)
+	}
+
+	const keypointsFilename = "keypoints.json"
+	analysisKey := keypointsKey[:len(keypointsKey)-len(keypointsFilename)] + "analysis.json"
+	url, err := p.store.Put(ctx, analysisKey, bytes.NewReader(encoded), "application/json")
+	if err != nil {
+		return fmt.Errorf("store analysis: %w", err)
+	}
+
+	recording.AnalysisPath = url
+	return nil
+}
+
+func main() {
+	cfg := config.Load()
+
+	db, err := gorm.Open(sqlite.Open(cfg.DBPath), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err)
+	}
+
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize storage backend: %v", err)
+	}
+
+	go serveMetrics(cfg.MetricsAddr)
+	go reportQueueDepth(cfg)
+
+	srv := asynq.NewServer(
+		queue.RedisOpt(cfg),
+		asynq.Config{
+			Concurrency: 10,
+			Queues:      map[string]int{"default": 1},
+			// Exponential backoff starting at 30s (30s, 60s, 120s, ...), matching
+			// EnqueueProcessRecording's doc comment. n is the number of retries
+			// so far, starting at 0 on the first failure.
+			RetryDelayFunc: func(n int, err error, t *asynq.Task) time.Duration {
+				return (30 * time.Second) << uint(n)
+			},
+		},
+	)
+
+	mux := asynq.NewServeMux()
+	mux.Handle(queue.TypeProcessRecording, &processor{db: db, store: store})
+
+	log.Println("Starting recording:process worker")
+	if err := srv.Run(mux); err != nil {
+		log.Fatalf("worker exited: %v", err)
+	}
+}
+
+func serveMetrics(addr string) {
+	http.Handle("/metrics", promhttp.Handler())
+	log.Printf("Serving worker metrics on %s/metrics", addr)
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		log.Printf("metrics server exited: %v", err)
+	}
+}
+
+// reportQueueDepth polls asynq's queue stats so handpose_worker_queue_depth
+// reflects backlog even between task executions.
+func reportQueueDepth(cfg *config.Config) {
+	inspector := asynq.NewInspector(queue.RedisOpt(cfg))
+	defer inspector.Close()
+
+	for range time.Tick(15 * time.Second) {
+		info, err := inspector.GetQueueInfo("default")
+		if err != nil {
+			log.Printf("failed to inspect queue: %v", err)
+			continue
+		}
+		metrics.QueueDepth.WithLabelValues("default").Set(float64(info.Pending + info.Active + info.Scheduled))
+	}
+}
@@ -0,0 +1,145 @@
+// Package analysis computes derived metrics from a recording's raw keypoints
+// JSON: per-joint range of motion, a tremor frequency estimate, and per-frame
+// validity. The result is what the worker persists as analysis.json.
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+const minJointConfidence = 0.5
+
+type Joint struct {
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Confidence float64 `json:"confidence"`
+}
+
+type Frame struct {
+	TimestampMS int64            `json:"t"`
+	Joints      map[string]Joint `json:"joints"`
+}
+
+type Result struct {
+	ROMDegrees    map[string]float64 `json:"rom_degrees"`
+	TremorHz      map[string]float64 `json:"tremor_hz"`
+	FrameValidity []bool             `json:"frame_validity"`
+}
+
+// Compute parses raw keypoints JSON (a list of Frame) and derives per-joint
+// ROM, a tremor frequency estimate, and per-frame validity.
+func Compute(keypoints []byte) (*Result, error) {
+	var frames []Frame
+	if err := json.Unmarshal(keypoints, &frames); err != nil {
+		return nil, fmt.Errorf("unmarshal keypoints: %w", err)
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no frames in keypoints")
+	}
+
+	series := map[string][]Joint{}
+	for _, frame := range frames {
+		for name, joint := range frame.Joints {
+			series[name] = append(series[name], joint)
+		}
+	}
+
+	result := &Result{
+		ROMDegrees:    make(map[string]float64, len(series)),
+		TremorHz:      make(map[string]float64, len(series)),
+		FrameValidity: make([]bool, len(frames)),
+	}
+
+	frameDurationMS := averageFrameDurationMS(frames)
+
+	for name, joints := range series {
+		result.ROMDegrees[name] = rangeOfMotionDegrees(joints)
+		result.TremorHz[name] = dominantFrequencyHz(joints, frameDurationMS)
+	}
+
+	for i, frame := range frames {
+		result.FrameValidity[i] = isFrameValid(frame)
+	}
+
+	return result, nil
+}
+
+func isFrameValid(frame Frame) bool {
+	if len(frame.Joints) == 0 {
+		return false
+	}
+	for _, joint := range frame.Joints {
+		if joint.Confidence < minJointConfidence {
+			return false
+		}
+	}
+	return true
+}
+
+func averageFrameDurationMS(frames []Frame) float64 {
+	if len(frames) < 2 {
+		return 0
+	}
+	return float64(frames[len(frames)-1].TimestampMS-frames[0].TimestampMS) / float64(len(frames)-1)
+}
+
+// rangeOfMotionDegrees approximates ROM as the angular spread, around the
+// joint's centroid, swept by its tracked positions.
+func rangeOfMotionDegrees(joints []Joint) float64 {
+	if len(joints) == 0 {
+		return 0
+	}
+
+	var cx, cy float64
+	for _, j := range joints {
+		cx += j.X
+		cy += j.Y
+	}
+	cx /= float64(len(joints))
+	cy /= float64(len(joints))
+
+	minAngle, maxAngle := math.Inf(1), math.Inf(-1)
+	for _, j := range joints {
+		angle := math.Atan2(j.Y-cy, j.X-cx)
+		if angle < minAngle {
+			minAngle = angle
+		}
+		if angle > maxAngle {
+			maxAngle = angle
+		}
+	}
+
+	return (maxAngle - minAngle) * 180 / math.Pi
+}
+
+// dominantFrequencyHz runs a naive DFT over the joint's X signal and returns
+// the frequency (excluding DC) with the largest magnitude — a tremor
+// estimate, not a precision spectral analysis.
+func dominantFrequencyHz(joints []Joint, frameDurationMS float64) float64 {
+	n := len(joints)
+	if n < 4 || frameDurationMS <= 0 {
+		return 0
+	}
+
+	sampleHz := 1000 / frameDurationMS
+
+	bestMag := 0.0
+	bestK := 0
+	for k := 1; k < n/2; k++ {
+		var re, im float64
+		for t, j := range joints {
+			theta := 2 * math.Pi * float64(k) * float64(t) / float64(n)
+			re += j.X * math.Cos(theta)
+			im -= j.X * math.Sin(theta)
+		}
+		mag := math.Hypot(re, im)
+		if mag > bestMag {
+			bestMag = mag
+			bestK = k
+		}
+	}
+
+	return float64(bestK) * sampleHz / float64(n)
+}
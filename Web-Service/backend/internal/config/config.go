@@ -0,0 +1,65 @@
+package config
+
+import "os"
+
+type Config struct {
+	Port      string
+	DBPath    string
+	JWTSecret string
+
+	// Storage backend selection: "local", "gcs", or "s3".
+	StorageBackend   string
+	LocalStoragePath string
+	// LocalSigningSecret HMAC-signs the local backend's SignedURLs. Kept
+	// independent of JWTSecret so rotating one doesn't affect the other.
+	LocalSigningSecret string
+
+	GCSBucket          string
+	GCSCredentialsPath string
+
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+	S3Bucket    string
+	S3UseSSL    bool
+
+	RedisAddr   string
+	MetricsAddr string
+
+	// PACSStowURL, when set, is the DICOMweb STOW-RS endpoint exports are
+	// pushed to after being written via the Storage backend.
+	PACSStowURL string
+}
+
+func Load() *Config {
+	return &Config{
+		Port:      getEnv("PORT", "4856"),
+		DBPath:    getEnv("DB_PATH", "users.db"),
+		JWTSecret: getEnv("JWT_SECRET", "dev-secret-change-me"),
+
+		StorageBackend:     getEnv("STORAGE_BACKEND", "local"),
+		LocalStoragePath:   getEnv("LOCAL_STORAGE_PATH", "./data"),
+		LocalSigningSecret: getEnv("LOCAL_SIGNING_SECRET", "dev-local-signing-secret-change-me"),
+
+		GCSBucket:          getEnv("GCS_BUCKET", ""),
+		GCSCredentialsPath: getEnv("GOOGLE_APPLICATION_CREDENTIALS", ""),
+
+		S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+		S3Bucket:    getEnv("S3_BUCKET", ""),
+		S3UseSSL:    getEnv("S3_USE_SSL", "true") == "true",
+
+		RedisAddr:   getEnv("REDIS_ADDR", "127.0.0.1:6379"),
+		MetricsAddr: getEnv("METRICS_ADDR", ":9090"),
+
+		PACSStowURL: getEnv("PACS_STOW_URL", ""),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
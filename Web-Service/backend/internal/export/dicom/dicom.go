@@ -0,0 +1,193 @@
+// Package dicom builds a DICOM Video Endoscopic Image Storage instance
+// (Transfer Syntax 1.2.840.10008.1.2.4.102, MPEG-4 AVC/H.264) wrapping a
+// recording's video and keypoint overlay, with the Patient module populated
+// from models.Patient. It implements just enough of DICOM part 10 (explicit
+// VR little endian file meta + encapsulated pixel data) to produce a file a
+// PACS can store and a viewer can open — not the full standard.
+package dicom
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/handpose/webservice/internal/models"
+)
+
+const (
+	sopClassVideoEndoscopicImageStorage = "1.2.840.10008.5.1.4.1.1.77.1.1.1"
+	transferSyntaxMPEG4                 = "1.2.840.10008.1.2.4.102"
+	implementationClassUID              = "1.2.840.10008.5.1.4.1.1.77.1.1.1.99.1"
+)
+
+// Build assembles a DICOM Part 10 file: file meta group followed by a
+// dataset carrying the Patient module and the video as encapsulated pixel
+// data, with the keypoint overlay stored as a private Overlay Comments
+// element (0x0009,0x0010 private creator / 0x0009,0x1001).
+func Build(patient *models.Patient, sopInstanceUID string, video []byte, overlay []byte, now time.Time) ([]byte, error) {
+	if sopInstanceUID == "" {
+		var err error
+		sopInstanceUID, err = newUID()
+		if err != nil {
+			return nil, fmt.Errorf("generate SOP instance UID: %w", err)
+		}
+	}
+
+	var dataset bytes.Buffer
+	writeElementShort(&dataset, 0x0008, 0x0016, "UI", padUID(sopClassVideoEndoscopicImageStorage))
+	writeElementShort(&dataset, 0x0008, 0x0018, "UI", padUID(sopInstanceUID))
+	writeElementShort(&dataset, 0x0008, 0x0060, "CS", padEven("XC")) // Modality: External-camera Photography
+
+	writeElementShort(&dataset, 0x0010, 0x0010, "PN", padEven(patient.Name))
+	writeElementShort(&dataset, 0x0010, 0x0020, "LO", padEven(patient.MRN))
+	writeElementShort(&dataset, 0x0010, 0x0030, "DA", padEven(dicomDate(patient.DateOfBirth)))
+	writeElementShort(&dataset, 0x0010, 0x0040, "CS", padEven(dicomSex(patient.Gender)))
+
+	writeElementShort(&dataset, 0x0008, 0x0050, "SH", padEven(patient.AccessionNumber))
+	writeElementShort(&dataset, 0x0008, 0x0020, "DA", padEven(now.Format("20060102")))
+
+	if len(overlay) > 0 {
+		writeElementShort(&dataset, 0x0009, 0x0010, "LO", padEven("HANDPOSE_OVERLAY"))
+		writeElementLong(&dataset, 0x0009, 0x1001, "OB", overlay)
+	}
+
+	writeEncapsulatedPixelData(&dataset, video)
+
+	meta, err := buildFileMeta(sopInstanceUID, dataset.Len())
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(make([]byte, 128)) // preamble
+	out.WriteString("DICM")
+	out.Write(meta)
+	out.Write(dataset.Bytes())
+
+	return out.Bytes(), nil
+}
+
+func buildFileMeta(sopInstanceUID string, datasetLen int) ([]byte, error) {
+	var body bytes.Buffer
+	writeElementShort(&body, 0x0002, 0x0002, "UI", padUID(sopClassVideoEndoscopicImageStorage))
+	writeElementShort(&body, 0x0002, 0x0003, "UI", padUID(sopInstanceUID))
+	writeElementShort(&body, 0x0002, 0x0010, "UI", padUID(transferSyntaxMPEG4))
+	writeElementShort(&body, 0x0002, 0x0012, "UI", padUID(implementationClassUID))
+
+	var meta bytes.Buffer
+	writeElementShort(&meta, 0x0002, 0x0000, "UL", encodeUint32(uint32(body.Len())))
+	meta.Write(body.Bytes())
+
+	return meta.Bytes(), nil
+}
+
+// writeElementShort writes an explicit-VR element using the short (2-byte
+// length) form, valid for VRs like UI/SH/LO/PN/CS/DA/UL.
+func writeElementShort(buf *bytes.Buffer, group, element uint16, vr string, value []byte) {
+	binary.Write(buf, binary.LittleEndian, group)
+	binary.Write(buf, binary.LittleEndian, element)
+	buf.WriteString(vr)
+	binary.Write(buf, binary.LittleEndian, uint16(len(value)))
+	buf.Write(value)
+}
+
+// writeElementLong writes an explicit-VR element using the long (4-byte
+// length, with 2 reserved bytes) form required for OB/OW/SQ/UN/UT.
+func writeElementLong(buf *bytes.Buffer, group, element uint16, vr string, value []byte) {
+	binary.Write(buf, binary.LittleEndian, group)
+	binary.Write(buf, binary.LittleEndian, element)
+	buf.WriteString(vr)
+	buf.Write([]byte{0x00, 0x00}) // reserved
+	binary.Write(buf, binary.LittleEndian, uint32(len(value)))
+	buf.Write(value)
+}
+
+// writeEncapsulatedPixelData wraps the MPEG-4 stream in DICOM's encapsulated
+// pixel data format: an OB element with undefined length, a (possibly empty)
+// Basic Offset Table item, one fragment item carrying the whole stream, and
+// a Sequence Delimitation Item.
+func writeEncapsulatedPixelData(buf *bytes.Buffer, video []byte) {
+	binary.Write(buf, binary.LittleEndian, uint16(0x7FE0))
+	binary.Write(buf, binary.LittleEndian, uint16(0x0010))
+	buf.WriteString("OB")
+	buf.Write([]byte{0x00, 0x00})
+	binary.Write(buf, binary.LittleEndian, uint32(0xFFFFFFFF)) // undefined length
+
+	writeItem(buf, nil) // empty Basic Offset Table
+	writeItem(buf, video)
+
+	// Sequence Delimitation Item (FFFE,E0DD), length 0.
+	binary.Write(buf, binary.LittleEndian, uint16(0xFFFE))
+	binary.Write(buf, binary.LittleEndian, uint16(0xE0DD))
+	binary.Write(buf, binary.LittleEndian, uint32(0))
+}
+
+func writeItem(buf *bytes.Buffer, data []byte) {
+	binary.Write(buf, binary.LittleEndian, uint16(0xFFFE))
+	binary.Write(buf, binary.LittleEndian, uint16(0xE000))
+	binary.Write(buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
+func encodeUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+// padEven returns s padded with a trailing space if its length is odd, as
+// required for DICOM string VRs.
+func padEven(s string) []byte {
+	b := []byte(s)
+	if len(b)%2 != 0 {
+		b = append(b, ' ')
+	}
+	return b
+}
+
+// padUID pads a UID string with a trailing NUL (not a space) per DICOM's UI
+// VR rule.
+func padUID(uid string) []byte {
+	b := []byte(uid)
+	if len(b)%2 != 0 {
+		b = append(b, 0x00)
+	}
+	return b
+}
+
+func dicomDate(dob string) string {
+	// Patient.DateOfBirth is a free-form MVP string; pass through values that
+	// already look like DICOM's YYYYMMDD and leave anything else blank
+	// rather than guess at a format.
+	if len(dob) == 8 {
+		return dob
+	}
+	return ""
+}
+
+func dicomSex(gender string) string {
+	switch gender {
+	case "M", "F", "O":
+		return gender
+	case "male", "Male":
+		return "M"
+	case "female", "Female":
+		return "F"
+	default:
+		return "O"
+	}
+}
+
+func newUID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	var n uint64
+	for _, v := range b {
+		n = n<<8 | uint64(v)
+	}
+	return fmt.Sprintf("2.25.%d", n), nil
+}
@@ -0,0 +1,126 @@
+// Package fhir builds hand-rolled FHIR R4 JSON for a recording: a Bundle
+// containing a Media resource for the video, an Observation per computed
+// joint metric, and a DiagnosticReport tying them together.
+package fhir
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/handpose/webservice/internal/analysis"
+	"github.com/handpose/webservice/internal/models"
+)
+
+type Reference struct {
+	Reference string `json:"reference"`
+	Display   string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Text string `json:"text"`
+}
+
+type Quantity struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+type Observation struct {
+	ResourceType string          `json:"resourceType"`
+	Status       string          `json:"status"`
+	Code         CodeableConcept `json:"code"`
+	Subject      Reference       `json:"subject"`
+	ValueQty     Quantity        `json:"valueQuantity"`
+}
+
+type Media struct {
+	ResourceType string    `json:"resourceType"`
+	Status       string    `json:"status"`
+	Subject      Reference `json:"subject"`
+	Content      struct {
+		ContentType string `json:"contentType"`
+		URL         string `json:"url"`
+		Title       string `json:"title"`
+	} `json:"content"`
+}
+
+type DiagnosticReport struct {
+	ResourceType string          `json:"resourceType"`
+	Status       string          `json:"status"`
+	Code         CodeableConcept `json:"code"`
+	Subject      Reference       `json:"subject"`
+	Effective    string          `json:"effectiveDateTime"`
+	Result       []Reference     `json:"result"`
+	Media        []struct {
+		Link Reference `json:"link"`
+	} `json:"media"`
+}
+
+type bundleEntry struct {
+	Resource interface{} `json:"resource"`
+}
+
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Entry        []bundleEntry `json:"entry"`
+}
+
+// BuildBundle assembles a transaction Bundle with a Media resource for the
+// video and an Observation per joint ROM/tremor metric, summarized by a
+// DiagnosticReport.
+func BuildBundle(patient *models.Patient, recording *models.Recording, result *analysis.Result, now time.Time) ([]byte, error) {
+	subject := Reference{
+		Reference: fmt.Sprintf("Patient/%s", patient.MRN),
+		Display:   patient.Name,
+	}
+
+	media := Media{ResourceType: "Media", Status: "completed", Subject: subject}
+	media.Content.ContentType = "video/webm"
+	media.Content.URL = recording.VideoPath
+	media.Content.Title = fmt.Sprintf("recording-%d", recording.ID)
+	mediaRef := Reference{Reference: fmt.Sprintf("Media/recording-%d", recording.ID)}
+
+	entries := []bundleEntry{{Resource: media}}
+	var observationRefs []Reference
+
+	for joint, rom := range result.ROMDegrees {
+		obs := Observation{
+			ResourceType: "Observation",
+			Status:       "final",
+			Code:         CodeableConcept{Text: fmt.Sprintf("%s range of motion", joint)},
+			Subject:      subject,
+			ValueQty:     Quantity{Value: rom, Unit: "deg"},
+		}
+		entries = append(entries, bundleEntry{Resource: obs})
+		observationRefs = append(observationRefs, Reference{Reference: fmt.Sprintf("Observation/%s-rom", joint)})
+	}
+	for joint, hz := range result.TremorHz {
+		obs := Observation{
+			ResourceType: "Observation",
+			Status:       "final",
+			Code:         CodeableConcept{Text: fmt.Sprintf("%s tremor frequency", joint)},
+			Subject:      subject,
+			ValueQty:     Quantity{Value: hz, Unit: "Hz"},
+		}
+		entries = append(entries, bundleEntry{Resource: obs})
+		observationRefs = append(observationRefs, Reference{Reference: fmt.Sprintf("Observation/%s-tremor", joint)})
+	}
+
+	report := DiagnosticReport{
+		ResourceType: "DiagnosticReport",
+		Status:       "final",
+		Code:         CodeableConcept{Text: "Hand-pose joint kinematics"},
+		Subject:      subject,
+		Effective:    now.Format(time.RFC3339),
+		Result:       observationRefs,
+	}
+	report.Media = append(report.Media, struct {
+		Link Reference `json:"link"`
+	}{Link: mediaRef})
+	entries = append(entries, bundleEntry{Resource: report})
+
+	bundle := Bundle{ResourceType: "Bundle", Type: "transaction", Entry: entries}
+	return json.MarshalIndent(bundle, "", "  ")
+}
@@ -0,0 +1,55 @@
+// Package pacs pushes finished export artifacts to a hospital PACS over
+// DICOMweb STOW-RS. Full DIMSE C-STORE is out of scope; STOW-RS covers the
+// common case of a PACS that also speaks DICOMweb.
+package pacs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+)
+
+const boundary = "HANDPOSE-STOW-BOUNDARY"
+
+// Push sends a single DICOM instance to a STOW-RS endpoint as a
+// multipart/related application/dicom payload.
+func Push(ctx context.Context, stowURL string, dicomBytes []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return fmt.Errorf("set boundary: %w", err)
+	}
+
+	part, err := writer.CreatePart(map[string][]string{
+		"Content-Type": {"application/dicom"},
+	})
+	if err != nil {
+		return fmt.Errorf("create part: %w", err)
+	}
+	if _, err := part.Write(dicomBytes); err != nil {
+		return fmt.Errorf("write part: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("close writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stowURL, &body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", fmt.Sprintf(`multipart/related; type="application/dicom"; boundary=%s`, boundary))
+	req.Header.Set("Accept", "application/dicom+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("stow-rs request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("stow-rs returned status %d", resp.StatusCode)
+	}
+	return nil
+}
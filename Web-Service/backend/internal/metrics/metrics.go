@@ -0,0 +1,20 @@
+// Package metrics holds the Prometheus collectors exposed by cmd/worker.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	QueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "handpose_worker_queue_depth",
+		Help: "Number of tasks currently pending, active, or scheduled in each asynq queue.",
+	}, []string{"queue"})
+
+	ProcessingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "handpose_worker_processing_duration_seconds",
+		Help:    "Time spent processing a recording:process task.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"outcome"})
+)
@@ -0,0 +1,48 @@
+package models
+
+import "gorm.io/gorm"
+
+type User struct {
+	gorm.Model
+	Email             string `gorm:"uniqueIndex"`
+	Password          string
+	FullName          string
+	PhoneNumber       string
+	HospitalInstitute string
+	Department        string
+	IsApproved        bool `gorm:"default:false"`
+	IsAdmin           bool `gorm:"default:false"`
+}
+
+type Project struct {
+	gorm.Model
+	Name        string
+	Description string
+	UserID      uint
+}
+
+type Patient struct {
+	gorm.Model
+	PatientID   string
+	Name        string
+	Gender      string
+	DateOfBirth string // Simplified date str for MVP
+	Height      float64
+	Weight      float64
+	ProjectID   uint
+
+	// MRN and AccessionNumber support mapping into hospital systems via the
+	// DICOM/FHIR export subsystem.
+	MRN             string
+	AccessionNumber string
+}
+
+type Recording struct {
+	gorm.Model
+	PatientID     uint
+	VideoPath     string
+	KeypointsPath string
+	AnalysisPath  string
+	Status        string // "uploaded", "processing", "completed", "failed"
+	Error         string
+}
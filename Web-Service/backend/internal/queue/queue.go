@@ -0,0 +1,53 @@
+// Package queue defines the background task types shared between the web
+// server (which enqueues them) and cmd/worker (which processes them).
+package queue
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/handpose/webservice/internal/config"
+)
+
+const TypeProcessRecording = "recording:process"
+
+// MaxRetry bounds how many times asynq retries a failed task before it is
+// moved to the archive (dead-letter) queue for manual inspection.
+const MaxRetry = 5
+
+type ProcessRecordingPayload struct {
+	RecordingID uint `json:"recording_id"`
+}
+
+func RedisOpt(cfg *config.Config) asynq.RedisClientOpt {
+	return asynq.RedisClientOpt{Addr: cfg.RedisAddr}
+}
+
+func NewClient(cfg *config.Config) *asynq.Client {
+	return asynq.NewClient(RedisOpt(cfg))
+}
+
+// NewProcessRecordingTask builds the task enqueued after a successful upload.
+func NewProcessRecordingTask(recordingID uint) (*asynq.Task, error) {
+	payload, err := json.Marshal(ProcessRecordingPayload{RecordingID: recordingID})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeProcessRecording, payload), nil
+}
+
+// EnqueueProcessRecording enqueues a recording for post-processing with
+// exponential backoff between retries, retrying up to MaxRetry times.
+func EnqueueProcessRecording(client *asynq.Client, recordingID uint) (*asynq.TaskInfo, error) {
+	task, err := NewProcessRecordingTask(recordingID)
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(task,
+		asynq.MaxRetry(MaxRetry),
+		asynq.Timeout(5*time.Minute),
+		asynq.Queue("default"),
+	)
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/handpose/webservice/internal/analysis"
+	"github.com/handpose/webservice/internal/export/dicom"
+	"github.com/handpose/webservice/internal/export/fhir"
+	"github.com/handpose/webservice/internal/export/pacs"
+	"github.com/handpose/webservice/internal/models"
+	"github.com/handpose/webservice/internal/storage"
+)
+
+// ExportFHIR generates a FHIR R4 Bundle (Media + per-joint Observations +
+// a summarizing DiagnosticReport) for a recording and stores it via the
+// Storage backend.
+func (s *Server) ExportFHIR(c *gin.Context) {
+	recording, patient, err := s.loadRecordingAndPatient(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.computeAnalysis(c, recording)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to compute analysis: %v", err)})
+		return
+	}
+
+	bundleJSON, err := fhir.BuildBundle(patient, recording, result, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build FHIR bundle"})
+		return
+	}
+
+	key := fmt.Sprintf("exports/recordings/%d/fhir-bundle.json", recording.ID)
+	url, err := s.store.Put(c.Request.Context(), key, bytes.NewReader(bundleJSON), "application/fhir+json")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store FHIR bundle"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+// ExportDICOM wraps the recording's video and keypoint overlay as a DICOM
+// Video Endoscopic Image Storage instance, stores it, and optionally pushes
+// it to a configured PACS over DICOMweb STOW-RS.
+func (s *Server) ExportDICOM(c *gin.Context) {
+	recording, patient, err := s.loadRecordingAndPatient(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	video, err := s.readObject(c, recording.VideoPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to read video: %v", err)})
+		return
+	}
+
+	overlay, err := s.readObject(c, recording.KeypointsPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to read keypoints overlay: %v", err)})
+		return
+	}
+
+	dicomBytes, err := dicom.Build(patient, "", video, overlay, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build DICOM instance"})
+		return
+	}
+
+	key := fmt.Sprintf("exports/recordings/%d/recording.dcm", recording.ID)
+	url, err := s.store.Put(c.Request.Context(), key, bytes.NewReader(dicomBytes), "application/dicom")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store DICOM instance"})
+		return
+	}
+
+	if s.cfg.PACSStowURL != "" {
+		if err := pacs.Push(c.Request.Context(), s.cfg.PACSStowURL, dicomBytes); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Stored but failed to push to PACS: %v", err), "url": url})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+func (s *Server) loadRecordingAndPatient(c *gin.Context) (*models.Recording, *models.Patient, error) {
+	recording, err := s.loadOwnedRecording(c, c.Param("id"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("recording not found")
+	}
+
+	var patient models.Patient
+	if err := s.db.First(&patient, recording.PatientID).Error; err != nil {
+		return nil, nil, fmt.Errorf("patient not found")
+	}
+
+	return recording, &patient, nil
+}
+
+func (s *Server) readObject(c *gin.Context, url string) ([]byte, error) {
+	r, err := s.store.Get(c.Request.Context(), storage.KeyFromURL(url))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// computeAnalysis prefers the worker's persisted analysis.json, falling
+// back to computing it inline if the recording hasn't finished processing.
+func (s *Server) computeAnalysis(c *gin.Context, recording *models.Recording) (*analysis.Result, error) {
+	if recording.AnalysisPath != "" {
+		raw, err := s.readObject(c, recording.AnalysisPath)
+		if err == nil {
+			var result analysis.Result
+			if err := json.Unmarshal(raw, &result); err == nil {
+				return &result, nil
+			}
+		}
+	}
+
+	keypoints, err := s.readObject(c, recording.KeypointsPath)
+	if err != nil {
+		return nil, err
+	}
+	return analysis.Compute(keypoints)
+}
@@ -0,0 +1,423 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+
+	"github.com/handpose/webservice/internal/auth"
+	"github.com/handpose/webservice/internal/config"
+	"github.com/handpose/webservice/internal/models"
+	"github.com/handpose/webservice/internal/queue"
+	"github.com/handpose/webservice/internal/storage"
+)
+
+// patientIDPattern restricts patient_id to characters safe to use as a
+// storage key path segment, so it can never be read as "." or ".." or
+// contain a path separator that would let it escape the key prefix the
+// backend builds around it (see UploadRecording).
+var patientIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+type Server struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	store storage.Storage
+	queue *asynq.Client
+}
+
+func New(db *gorm.DB, cfg *config.Config, store storage.Storage, queueClient *asynq.Client) *Server {
+	return &Server{db: db, cfg: cfg, store: store, queue: queueClient}
+}
+
+func (s *Server) Login(c *gin.Context) {
+	var body struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	var user models.User
+	result := s.db.Where("email = ?", body.Email).First(&user)
+	if result.Error != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
+	if !auth.CheckPassword(user.Password, body.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
+	if !user.IsApproved {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account waiting for approval"})
+		return
+	}
+
+	token, err := auth.GenerateToken(&user, s.cfg.JWTSecret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"token":   token,
+		"email":   user.Email,
+		"name":    user.FullName,
+	})
+}
+
+func (s *Server) Register(c *gin.Context) {
+	var body struct {
+		Email             string `json:"email"`
+		Password          string `json:"password"`
+		FullName          string `json:"full_name"`
+		PhoneNumber       string `json:"phone_number"`
+		HospitalInstitute string `json:"hospital_institute"`
+		Department        string `json:"department"`
+	}
+
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	hashed, err := auth.HashPassword(body.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
+		return
+	}
+
+	user := models.User{
+		Email:             body.Email,
+		Password:          hashed,
+		FullName:          body.FullName,
+		PhoneNumber:       body.PhoneNumber,
+		HospitalInstitute: body.HospitalInstitute,
+		Department:        body.Department,
+		IsApproved:        false, // Default to pending
+	}
+
+	result := s.db.Create(&user)
+	if result.Error != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User already exists"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Waiting approval..."})
+}
+
+func (s *Server) CreateProject(c *gin.Context) {
+	user, _ := auth.CurrentUser(c)
+
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	proj := models.Project{
+		Name:        body.Name,
+		Description: body.Description,
+		UserID:      user.ID,
+	}
+	s.db.Create(&proj)
+
+	c.JSON(http.StatusCreated, proj)
+}
+
+func (s *Server) ListProjects(c *gin.Context) {
+	user, _ := auth.CurrentUser(c)
+
+	var projects []models.Project
+	s.db.Where("user_id = ?", user.ID).Find(&projects)
+	c.JSON(http.StatusOK, projects)
+}
+
+func (s *Server) CreatePatient(c *gin.Context) {
+	var body struct {
+		PatientID       string  `json:"patient_id"`
+		Name            string  `json:"name"`
+		Gender          string  `json:"gender"`
+		DateOfBirth     string  `json:"dob"`
+		Height          float64 `json:"height"`
+		Weight          float64 `json:"weight"`
+		ProjectID       uint    `json:"project_id"`
+		MRN             string  `json:"mrn"`
+		AccessionNumber string  `json:"accession_number"`
+	}
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if !s.userOwnsProject(c, body.ProjectID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Project not found"})
+		return
+	}
+
+	if !patientIDPattern.MatchString(body.PatientID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "patient_id must match " + patientIDPattern.String()})
+		return
+	}
+
+	patient := models.Patient{
+		PatientID:       body.PatientID,
+		Name:            body.Name,
+		Gender:          body.Gender,
+		DateOfBirth:     body.DateOfBirth,
+		Height:          body.Height,
+		Weight:          body.Weight,
+		ProjectID:       body.ProjectID,
+		MRN:             body.MRN,
+		AccessionNumber: body.AccessionNumber,
+	}
+	s.db.Create(&patient)
+
+	c.JSON(http.StatusCreated, patient)
+}
+
+func (s *Server) ListPatients(c *gin.Context) {
+	user, _ := auth.CurrentUser(c)
+
+	projectID := c.Query("project_id")
+	var patients []models.Patient
+	q := s.db.Joins("JOIN projects ON projects.id = patients.project_id").
+		Where("projects.user_id = ?", user.ID)
+	if projectID != "" {
+		q = q.Where("patients.project_id = ?", projectID)
+	}
+	q.Find(&patients)
+	c.JSON(http.StatusOK, patients)
+}
+
+// userOwnsProject reports whether the authenticated caller owns projectID,
+// or is an admin.
+func (s *Server) userOwnsProject(c *gin.Context, projectID uint) bool {
+	user, _ := auth.CurrentUser(c)
+	if user.IsAdmin {
+		return true
+	}
+	var project models.Project
+	err := s.db.Where("id = ? AND user_id = ?", projectID, user.ID).First(&project).Error
+	return err == nil
+}
+
+// loadOwnedPatient fetches the patient with the given ID, scoped to the
+// authenticated caller's own projects (same join used by ListPatients).
+// Admins bypass the scope. A missing or not-owned patient both report 404 to
+// avoid leaking cross-tenant existence.
+func (s *Server) loadOwnedPatient(c *gin.Context, patientID string) (*models.Patient, error) {
+	user, _ := auth.CurrentUser(c)
+	var patient models.Patient
+	q := s.db.Select("patients.*").
+		Joins("JOIN projects ON projects.id = patients.project_id").
+		Where("patients.id = ?", patientID)
+	if !user.IsAdmin {
+		q = q.Where("projects.user_id = ?", user.ID)
+	}
+	if err := q.First(&patient).Error; err != nil {
+		return nil, err
+	}
+	return &patient, nil
+}
+
+// loadOwnedRecording fetches the recording with the given ID, scoped to the
+// authenticated caller's own projects via its patient. Admins bypass the
+// scope.
+func (s *Server) loadOwnedRecording(c *gin.Context, recordingID string) (*models.Recording, error) {
+	user, _ := auth.CurrentUser(c)
+	var recording models.Recording
+	q := s.db.Select("recordings.*").
+		Joins("JOIN patients ON patients.id = recordings.patient_id").
+		Joins("JOIN projects ON projects.id = patients.project_id").
+		Where("recordings.id = ?", recordingID)
+	if !user.IsAdmin {
+		q = q.Where("projects.user_id = ?", user.ID)
+	}
+	if err := q.First(&recording).Error; err != nil {
+		return nil, err
+	}
+	return &recording, nil
+}
+
+func (s *Server) UploadRecording(c *gin.Context) {
+	patientIDStr := c.Param("id")
+	patientID, err := strconv.Atoi(patientIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID"})
+		return
+	}
+
+	// 1. Get files
+	videoFile, err := c.FormFile("video")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Video file missing"})
+		return
+	}
+
+	keypointsFile, err := c.FormFile("keypoints")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Keypoints file missing"})
+		return
+	}
+
+	// 2. Find Patient to get Project ID
+	patient, err := s.loadOwnedPatient(c, patientIDStr)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Patient not found"})
+		return
+	}
+
+	// projects/{project_id}/{patient_id}/{timestamp}/
+	ts := time.Now().Format("20060102-150405")
+	keyPrefix := fmt.Sprintf("projects/%d/%s/%s", patient.ProjectID, patient.PatientID, ts)
+
+	// 3. Store files via the configured Storage backend
+	videoSrc, err := videoFile.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open video"})
+		return
+	}
+	defer videoSrc.Close()
+
+	videoURL, err := s.store.Put(c.Request.Context(), keyPrefix+"/recording.webm", videoSrc, videoFile.Header.Get("Content-Type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save video"})
+		return
+	}
+
+	keypointsSrc, err := keypointsFile.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open keypoints"})
+		return
+	}
+	defer keypointsSrc.Close()
+
+	keypointsURL, err := s.store.Put(c.Request.Context(), keyPrefix+"/keypoints.json", keypointsSrc, "application/json")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save keypoints"})
+		return
+	}
+
+	// 4. Record in DB
+	recording := models.Recording{
+		PatientID:     uint(patientID),
+		VideoPath:     videoURL,
+		KeypointsPath: keypointsURL,
+		Status:        "uploaded",
+	}
+	s.db.Create(&recording)
+
+	if _, err := queue.EnqueueProcessRecording(s.queue, recording.ID); err != nil {
+		// The upload itself succeeded; surface the enqueue failure via Status
+		// rather than failing the request.
+		recording.Status = "failed"
+		recording.Error = fmt.Sprintf("failed to enqueue processing: %v", err)
+		s.db.Save(&recording)
+	}
+
+	c.JSON(http.StatusCreated, recording)
+}
+
+// GetRecordingStatus reports the current processing status for polling
+// clients.
+func (s *Server) GetRecordingStatus(c *gin.Context) {
+	recording, err := s.loadOwnedRecording(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recording not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": recording.Status,
+		"error":  recording.Error,
+	})
+}
+
+// RejudgeRecording re-enqueues a recording for post-processing. Admin-only.
+func (s *Server) RejudgeRecording(c *gin.Context) {
+	recording, err := s.loadOwnedRecording(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recording not found"})
+		return
+	}
+
+	recording.Status = "uploaded"
+	recording.Error = ""
+	s.db.Save(recording)
+
+	if _, err := queue.EnqueueProcessRecording(s.queue, recording.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue processing"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, recording)
+}
+
+// GetRecordingVideo redirects to a signed, time-limited URL for the
+// recording's video instead of serving PHI from a public static mount.
+func (s *Server) GetRecordingVideo(c *gin.Context) {
+	recording, err := s.loadOwnedRecording(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recording not found"})
+		return
+	}
+
+	url, err := s.store.SignedURL(c.Request.Context(), storage.KeyFromURL(recording.VideoPath), 15*time.Minute)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign video URL"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+func (s *Server) ListRecordings(c *gin.Context) {
+	patient, err := s.loadOwnedPatient(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Patient not found"})
+		return
+	}
+
+	var recordings []models.Recording
+	s.db.Where("patient_id = ?", patient.ID).Find(&recordings)
+	c.JSON(http.StatusOK, recordings)
+}
+
+// ApproveUser flips IsApproved on the target user. Admin-only.
+func (s *Server) ApproveUser(c *gin.Context) {
+	var user models.User
+	if err := s.db.First(&user, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user.IsApproved = true
+	s.db.Save(&user)
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DB exposes the underlying database handle for route registration helpers.
+func (s *Server) DB() *gorm.DB {
+	return s.db
+}
+
+// Config exposes the server's config for route registration helpers.
+func (s *Server) Config() *config.Config {
+	return s.cfg
+}
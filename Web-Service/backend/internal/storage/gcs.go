@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	gcs "cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+
+	"github.com/handpose/webservice/internal/config"
+)
+
+// GCS stores objects in a Google Cloud Storage bucket.
+type GCS struct {
+	client *gcs.Client
+	bucket string
+}
+
+func NewGCS(cfg *config.Config) (*GCS, error) {
+	ctx := context.Background()
+
+	var opts []option.ClientOption
+	if cfg.GCSCredentialsPath != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.GCSCredentialsPath))
+	}
+
+	client, err := gcs.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gcs.NewClient: %w", err)
+	}
+
+	return &GCS{client: client, bucket: cfg.GCSBucket}, nil
+}
+
+func (g *GCS) Put(ctx context.Context, key string, content io.Reader, contentType string) (string, error) {
+	w := g.client.Bucket(g.bucket).Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+
+	if _, err := io.Copy(w, content); err != nil {
+		return "", fmt.Errorf("io.Copy: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("Writer.Close: %w", err)
+	}
+
+	return fmt.Sprintf("gs://%s/%s", g.bucket, key), nil
+}
+
+func (g *GCS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return g.client.Bucket(g.bucket).Object(key).NewReader(ctx)
+}
+
+func (g *GCS) Delete(ctx context.Context, key string) error {
+	return g.client.Bucket(g.bucket).Object(key).Delete(ctx)
+}
+
+func (g *GCS) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return g.client.Bucket(g.bucket).SignedURL(key, &gcs.SignedURLOptions{
+		Method:  "GET",
+		Expires: time.Now().Add(ttl),
+	})
+}
+
+func (g *GCS) Close() error {
+	return g.client.Close()
+}
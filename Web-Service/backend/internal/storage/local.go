@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalFS stores objects as files under a root directory on local disk.
+// SignedURL and Handler together give it the same time-limited-access
+// semantics as the cloud backends: URLs carry an HMAC over the key and an
+// expiry, and Handler verifies both before serving a file.
+type LocalFS struct {
+	root   string
+	secret string
+}
+
+func NewLocalFS(root, secret string) *LocalFS {
+	return &LocalFS{root: root, secret: secret}
+}
+
+func (l *LocalFS) Put(ctx context.Context, key string, content io.Reader, contentType string) (string, error) {
+	path, err := l.resolve(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("mkdir: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, content); err != nil {
+		return "", fmt.Errorf("write: %w", err)
+	}
+
+	return "local://" + key, nil
+}
+
+func (l *LocalFS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (l *LocalFS) Delete(ctx context.Context, key string) error {
+	path, err := l.resolve(key)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// resolve joins key onto root and rejects it if the result would land
+// outside root, e.g. because key contains ".." segments. Callers are
+// expected to already produce well-formed keys, but storage is the last
+// line of defense against a key built from unsanitized user input.
+func (l *LocalFS) resolve(key string) (string, error) {
+	root, err := filepath.Abs(l.root)
+	if err != nil {
+		return "", fmt.Errorf("resolve root: %w", err)
+	}
+	path := filepath.Join(root, filepath.FromSlash(key))
+	if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("key %q escapes storage root", key)
+	}
+	return path, nil
+}
+
+// SignedURL returns a path under /data carrying an expiry and an HMAC over
+// the key and expiry, so Handler can verify the request without the file
+// being world-readable.
+func (l *LocalFS) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	expires := time.Now().Add(ttl).Unix()
+	return fmt.Sprintf("/data/%s?expires=%d&sig=%s", key, expires, l.sign(key, expires)), nil
+}
+
+// Handler serves objects previously returned by SignedURL, rejecting
+// requests with a missing, expired, or incorrect signature. It replaces the
+// plain r.Static("/data", ...) mount that served PHI unauthenticated.
+func (l *LocalFS) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/data/")
+
+		expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+		if err != nil || time.Now().Unix() > expires {
+			http.Error(w, "expired or invalid URL", http.StatusForbidden)
+			return
+		}
+
+		sig := r.URL.Query().Get("sig")
+		if !hmac.Equal([]byte(sig), []byte(l.sign(key, expires))) {
+			http.Error(w, "expired or invalid URL", http.StatusForbidden)
+			return
+		}
+
+		path, err := l.resolve(key)
+		if err != nil {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		http.ServeFile(w, r, path)
+	})
+}
+
+func (l *LocalFS) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(l.secret))
+	fmt.Fprintf(mac, "%s:%d", key, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
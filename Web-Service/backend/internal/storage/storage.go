@@ -0,0 +1,56 @@
+// Package storage abstracts where recording videos and keypoint files live so
+// the handlers don't need to care whether a given deployment backs onto local
+// disk, GCS, or S3/MinIO.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/handpose/webservice/internal/config"
+)
+
+// Storage is implemented by each supported backend.
+type Storage interface {
+	// Put writes content under key and returns a URL identifying the object.
+	Put(ctx context.Context, key string, content io.Reader, contentType string) (string, error)
+	// Get opens the object stored at key for reading.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored at key.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a time-limited URL for the object at key.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// New constructs the Storage backend selected by cfg.StorageBackend.
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageBackend {
+	case "gcs":
+		return NewGCS(cfg)
+	case "s3":
+		return NewS3(cfg)
+	case "local", "":
+		return NewLocalFS(cfg.LocalStoragePath, cfg.LocalSigningSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
+
+// KeyFromURL strips the scheme and bucket name a backend prefixed onto a
+// stored URL (e.g. "gs://bucket/key" or "local://key"), leaving the bare key
+// to hand back to Get/SignedURL.
+func KeyFromURL(url string) string {
+	rest := url
+	if idx := strings.Index(rest, "://"); idx != -1 {
+		rest = rest[idx+len("://"):]
+	}
+	if strings.HasPrefix(url, "gs://") || strings.HasPrefix(url, "s3://") {
+		if idx := strings.Index(rest, "/"); idx != -1 {
+			rest = rest[idx+1:]
+		}
+	}
+	return rest
+}
@@ -1,16 +1,31 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/handpose/ingestion/internal/config"
 	"github.com/handpose/ingestion/internal/handler"
 	"github.com/handpose/ingestion/internal/storage"
+	"github.com/handpose/ingestion/internal/store"
 )
 
+// reapInterval is how often expired upload sessions (and their orphaned GCS
+// chunks) are swept, relative to how long UploadSessionTTL lets one live.
+const reapInterval = 1 * time.Hour
+
+// reapTimeout bounds a single sweep so a stalled GCS call can't wedge the
+// reaper forever; time.Tick drops ticks it can't deliver, so a sweep that
+// never returns would otherwise silently stop the reaper for good.
+const reapTimeout = 5 * time.Minute
+
 func main() {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
 
 	// Init GCS
 	gcsClient, err := storage.NewGCSClient(cfg)
@@ -19,12 +34,21 @@ func main() {
 	}
 	defer gcsClient.Close()
 
+	// Init upload-session metadata store
+	sessionStore, err := store.Open(cfg.DBDSN)
+	if err != nil {
+		log.Fatalf("Failed to initialize session store: %v", err)
+	}
+
 	// Router
 	r := gin.Default()
-	
+
 	// Handlers
 	uploadHandler := handler.NewUploadHandler(gcsClient)
-	
+	tusHandler := handler.NewTusHandler(gcsClient, sessionStore, cfg)
+
+	go reapExpiredSessions(tusHandler)
+
 	// Routes
 	v1 := r.Group("/v1")
 	{
@@ -32,6 +56,12 @@ func main() {
 		v1.GET("/health", func(c *gin.Context) {
 			c.JSON(200, gin.H{"status": "ok"})
 		})
+
+		// tus v1.0.0 resumable upload protocol
+		v1.POST("/files", tusHandler.CreateUpload)
+		v1.HEAD("/files/:id", tusHandler.HeadUpload)
+		v1.PATCH("/files/:id", tusHandler.PatchUpload)
+		v1.DELETE("/files/:id", tusHandler.DeleteUpload)
 	}
 
 	log.Printf("Starting Ingestion Service on port %s", cfg.Port)
@@ -39,3 +69,13 @@ func main() {
 		log.Fatalf("Failed to run server: %v", err)
 	}
 }
+
+// reapExpiredSessions periodically sweeps upload sessions past their TTL, so
+// abandoned tus uploads don't accumulate in the DB or the bucket forever.
+func reapExpiredSessions(h *handler.TusHandler) {
+	for range time.Tick(reapInterval) {
+		ctx, cancel := context.WithTimeout(context.Background(), reapTimeout)
+		h.ReapExpired(ctx)
+		cancel()
+	}
+}
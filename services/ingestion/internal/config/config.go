@@ -1,21 +1,97 @@
+// Package config loads ingestion service settings from environment
+// variables, falling back to a config.yaml file for values left unset. Env
+// vars always win over the file so container deployments can override a
+// checked-in config.yaml without editing it.
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Port            string
-	GCSBucket       string
-	CredentialsPath string
+	Port string `yaml:"port"`
+
+	// Storage backend selection, e.g. "gcs".
+	StorageBackend  string `yaml:"storage_backend"`
+	GCSBucket       string `yaml:"gcs_bucket"`
+	CredentialsPath string `yaml:"credentials_path"`
+	ObjectPrefix    string `yaml:"object_prefix"`
+
+	JWTSecret string `yaml:"jwt_secret"`
+	DBDSN     string `yaml:"db_dsn"`
+	RedisAddr string `yaml:"redis_addr"`
+
+	MaxUploadMB int `yaml:"max_upload_mb"`
+
+	// UploadSessionTTL bounds how long an incomplete tus upload session may
+	// be resumed before HEAD/PATCH start rejecting it.
+	UploadSessionTTL time.Duration `yaml:"-"`
+}
+
+// Load reads config.yaml (if present) for defaults, then applies environment
+// variable overrides, and validates the result.
+func Load() (*Config, error) {
+	cfg := &Config{
+		Port:           "8080",
+		StorageBackend: "gcs",
+		ObjectPrefix:   "dev-handpose",
+		MaxUploadMB:    500,
+		DBDSN:          "uploads.db",
+
+		UploadSessionTTL: 24 * time.Hour,
+	}
+
+	if err := mergeYAMLFile(cfg, "config.yaml"); err != nil {
+		return nil, fmt.Errorf("loading config.yaml: %w", err)
+	}
+
+	applyEnvOverrides(cfg)
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func mergeYAMLFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, cfg)
+}
+
+func applyEnvOverrides(cfg *Config) {
+	cfg.Port = getEnv("PORT", cfg.Port)
+	cfg.StorageBackend = getEnv("STORAGE_BACKEND", cfg.StorageBackend)
+	cfg.GCSBucket = getEnv("GCS_BUCKET", cfg.GCSBucket)
+	cfg.CredentialsPath = getEnv("GOOGLE_APPLICATION_CREDENTIALS", cfg.CredentialsPath)
+	cfg.ObjectPrefix = getEnv("OBJECT_PREFIX", cfg.ObjectPrefix)
+	cfg.JWTSecret = getEnv("JWT_SECRET", cfg.JWTSecret)
+	cfg.DBDSN = getEnv("DB_DSN", cfg.DBDSN)
+	cfg.RedisAddr = getEnv("REDIS_ADDR", cfg.RedisAddr)
+
+	if v, ok := os.LookupEnv("MAX_UPLOAD_MB"); ok {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			cfg.MaxUploadMB = parsed
+		}
+	}
 }
 
-func Load() *Config {
-	return &Config{
-		Port:            getEnv("PORT", "8080"),
-		GCSBucket:       "coral-shoreline-435307-k0.firebasestorage.app", // Using the bucket from user request (stem)
-		CredentialsPath: "/home/shivam/Desktop/HandPose/GCS key/coral-shoreline-435307-k0-0d200fc43406.json",
+func (c *Config) validate() error {
+	if c.StorageBackend == "gcs" && c.GCSBucket == "" {
+		return fmt.Errorf("GCS_BUCKET is required when STORAGE_BACKEND=gcs")
 	}
+	return nil
 }
 
 func getEnv(key, fallback string) string {
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/handpose/ingestion/internal/config"
+	"github.com/handpose/ingestion/internal/storage"
+	"github.com/handpose/ingestion/internal/store"
+)
+
+const tusVersion = "1.0.0"
+
+type TusHandler struct {
+	storage        *storage.GCSClient
+	store          *store.Store
+	ttl            time.Duration
+	objectPrefix   string
+	maxUploadBytes int64
+}
+
+func NewTusHandler(s *storage.GCSClient, st *store.Store, cfg *config.Config) *TusHandler {
+	return &TusHandler{
+		storage:        s,
+		store:          st,
+		ttl:            cfg.UploadSessionTTL,
+		objectPrefix:   cfg.ObjectPrefix,
+		maxUploadBytes: int64(cfg.MaxUploadMB) * 1024 * 1024,
+	}
+}
+
+// CreateUpload handles POST /v1/files: it opens a new upload session sized
+// by the Upload-Length header and returns its Location for subsequent
+// HEAD/PATCH calls.
+func (h *TusHandler) CreateUpload(c *gin.Context) {
+	totalSize, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || totalSize < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Length header is required"})
+		return
+	}
+	if totalSize > h.maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Upload-Length exceeds the %d MB limit", h.maxUploadBytes/(1024*1024))})
+		return
+	}
+
+	meta := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	filename := meta["filename"]
+	if filename == "" {
+		filename = "recording.webm"
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	session := &store.UploadSession{
+		SessionID: sessionID,
+		PatientID: meta["patient_id"],
+		Filename:  filename,
+		TotalSize: totalSize,
+		Offset:    0,
+		ExpiresAt: time.Now().Add(h.ttl),
+	}
+	if err := h.store.Create(session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Location", "/v1/files/"+sessionID)
+	c.Header("Upload-Offset", "0")
+	c.Status(http.StatusCreated)
+}
+
+// HeadUpload handles HEAD /v1/files/:id, reporting the offset the client
+// should resume PATCHing from.
+func (h *TusHandler) HeadUpload(c *gin.Context) {
+	session, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if sessionExpired(session) {
+		c.Status(http.StatusGone)
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.TotalSize, 10))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
+
+// PatchUpload handles PATCH /v1/files/:id, appending one chunk at the
+// client-supplied Upload-Offset. On the chunk that completes the upload, the
+// session's parts are composed into the final GCS object.
+func (h *TusHandler) PatchUpload(c *gin.Context) {
+	if ct := c.GetHeader("Content-Type"); ct != "application/offset+octet-stream" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be application/offset+octet-stream"})
+		return
+	}
+
+	session, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if sessionExpired(session) {
+		c.Status(http.StatusGone)
+		return
+	}
+	if session.Complete {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload already completed"})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset != session.Offset {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload-Offset does not match session offset"})
+		return
+	}
+	if offset >= h.maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Upload exceeds the %d MB limit", h.maxUploadBytes/(1024*1024))})
+		return
+	}
+
+	remaining := session.TotalSize - offset
+	if max := h.maxUploadBytes - offset; max < remaining {
+		remaining = max
+	}
+	n, err := h.storage.WriteChunk(c.Request.Context(), session.SessionID, offset, io.LimitReader(c.Request.Body, remaining))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to write chunk: %v", err)})
+		return
+	}
+
+	newOffset := offset + n
+	if err := h.store.UpdateOffset(session.SessionID, newOffset); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to persist offset"})
+		return
+	}
+
+	if newOffset >= session.TotalSize {
+		destKey := fmt.Sprintf("%s/sessions/%s/%s", h.objectPrefix, session.SessionID, session.Filename)
+		if _, err := h.storage.ComposeSession(c.Request.Context(), session.SessionID, destKey); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to finalize upload: %v", err)})
+			return
+		}
+		if err := h.store.MarkComplete(session.SessionID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark upload complete"})
+			return
+		}
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Upload-Offset", strconv.FormatInt(newOffset, 10))
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteUpload handles DELETE /v1/files/:id, aborting an in-progress upload
+// and discarding its temporary chunks.
+func (h *TusHandler) DeleteUpload(c *gin.Context) {
+	session, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	if err := h.storage.AbortSession(c.Request.Context(), session.SessionID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to abort upload"})
+		return
+	}
+	if err := h.store.Delete(session.SessionID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Status(http.StatusNoContent)
+}
+
+// sessionExpired reports whether session is past the point HEAD/PATCH should
+// start rejecting it.
+func sessionExpired(session *store.UploadSession) bool {
+	return time.Now().After(session.ExpiresAt)
+}
+
+// reapGrace is subtracted from the reap cutoff so the reaper never touches a
+// session so recently expired that a PATCH already past its own expiry check
+// could still be mid-write; it bounds how long a straggling PATCH can run.
+const reapGrace = 5 * time.Minute
+
+// ReapExpired aborts and deletes every incomplete session whose ExpiresAt
+// and last update both passed more than reapGrace ago (see
+// store.ListExpired), freeing the GCS chunks it never finished
+// uploading. Callers run it on a ticker.
+func (h *TusHandler) ReapExpired(ctx context.Context) {
+	expired, err := h.store.ListExpired(time.Now().Add(-reapGrace))
+	if err != nil {
+		log.Printf("reaper: failed to list expired sessions: %v", err)
+		return
+	}
+
+	for _, session := range expired {
+		if err := h.storage.AbortSession(ctx, session.SessionID); err != nil {
+			log.Printf("reaper: failed to abort session %s: %v", session.SessionID, err)
+			continue
+		}
+		if err := h.store.Delete(session.SessionID); err != nil {
+			log.Printf("reaper: failed to delete session %s: %v", session.SessionID, err)
+		}
+	}
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header: a comma
+// separated list of "key base64(value)" pairs.
+func parseUploadMetadata(header string) map[string]string {
+	meta := map[string]string{}
+	if header == "" {
+		return meta
+	}
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			continue
+		}
+		meta[parts[0]] = string(value)
+	}
+	return meta
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"time"
+	"sort"
 
 	"cloud.google.com/go/storage"
 	"github.com/handpose/ingestion/internal/config"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -18,7 +19,16 @@ type GCSClient struct {
 
 func NewGCSClient(cfg *config.Config) (*GCSClient, error) {
 	ctx := context.Background()
-	client, err := storage.NewClient(ctx, option.WithCredentialsFile(cfg.CredentialsPath))
+
+	// An empty CredentialsPath means rely on Application Default
+	// Credentials, which covers GKE Workload Identity and Cloud Run service
+	// accounts as well as `gcloud auth application-default login` locally.
+	var opts []option.ClientOption
+	if cfg.CredentialsPath != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsPath))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %v", err)
 	}
@@ -32,11 +42,11 @@ func NewGCSClient(cfg *config.Config) (*GCSClient, error) {
 func (g *GCSClient) UploadResponse(ctx context.Context, sessionID string, filename string, content io.Reader) (string, error) {
 	objectName := fmt.Sprintf("sessions/%s/%s", sessionID, filename)
 	wc := g.client.Bucket(g.bucket).Object(objectName).NewWriter(ctx)
-	
+
 	if _, err := io.Copy(wc, content); err != nil {
 		return "", fmt.Errorf("io.Copy: %v", err)
 	}
-	
+
 	if err := wc.Close(); err != nil {
 		return "", fmt.Errorf("Writer.Close: %v", err)
 	}
@@ -44,6 +54,92 @@ func (g *GCSClient) UploadResponse(ctx context.Context, sessionID string, filena
 	return fmt.Sprintf("gs://%s/%s", g.bucket, objectName), nil
 }
 
+// tempChunkName builds the path for an in-progress tus chunk: parts are
+// named by their starting byte offset so ComposeSession can stitch them back
+// together in order.
+func tempChunkName(sessionID string, offset int64) string {
+	return fmt.Sprintf("tmp/uploads/%s/%020d", sessionID, offset)
+}
+
+// WriteChunk uploads a single tus PATCH chunk as a temporary resumable
+// object, using the GCS client's own resumable session under the hood
+// (Writer.ChunkSize) so a single PATCH can itself be retried.
+func (g *GCSClient) WriteChunk(ctx context.Context, sessionID string, offset int64, content io.Reader) (int64, error) {
+	w := g.client.Bucket(g.bucket).Object(tempChunkName(sessionID, offset)).NewWriter(ctx)
+	w.ChunkSize = 8 << 20
+
+	n, err := io.Copy(w, content)
+	if err != nil {
+		return 0, fmt.Errorf("io.Copy: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return 0, fmt.Errorf("Writer.Close: %v", err)
+	}
+
+	return n, nil
+}
+
+// ComposeSession composes every chunk written for sessionID, in byte-offset
+// order, into the final object at destKey, then deletes the temporary
+// chunks. The move is atomic from a reader's perspective: destKey only
+// appears once compose succeeds.
+func (g *GCSClient) ComposeSession(ctx context.Context, sessionID string, destKey string) (string, error) {
+	bucket := g.client.Bucket(g.bucket)
+
+	var chunkNames []string
+	it := bucket.Objects(ctx, &storage.Query{Prefix: fmt.Sprintf("tmp/uploads/%s/", sessionID)})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("listing chunks: %v", err)
+		}
+		chunkNames = append(chunkNames, attrs.Name)
+	}
+	sort.Strings(chunkNames)
+
+	if len(chunkNames) == 0 {
+		return "", fmt.Errorf("no chunks found for session %s", sessionID)
+	}
+
+	srcs := make([]*storage.ObjectHandle, 0, len(chunkNames))
+	for _, name := range chunkNames {
+		srcs = append(srcs, bucket.Object(name))
+	}
+
+	dest := bucket.Object(destKey)
+	if _, err := dest.ComposerFrom(srcs...).Run(ctx); err != nil {
+		return "", fmt.Errorf("compose: %v", err)
+	}
+
+	for _, name := range chunkNames {
+		_ = bucket.Object(name).Delete(ctx)
+	}
+
+	return fmt.Sprintf("gs://%s/%s", g.bucket, destKey), nil
+}
+
+// AbortSession deletes every temporary chunk written for sessionID.
+func (g *GCSClient) AbortSession(ctx context.Context, sessionID string) error {
+	bucket := g.client.Bucket(g.bucket)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: fmt.Sprintf("tmp/uploads/%s/", sessionID)})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("listing chunks: %v", err)
+		}
+		if err := bucket.Object(attrs.Name).Delete(ctx); err != nil {
+			return fmt.Errorf("delete chunk %s: %v", attrs.Name, err)
+		}
+	}
+	return nil
+}
+
 func (g *GCSClient) Close() error {
 	return g.client.Close()
 }
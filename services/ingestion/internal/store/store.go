@@ -0,0 +1,77 @@
+// Package store persists tus upload-session metadata so HEAD requests (and
+// resumption after a service restart) can report the correct offset.
+package store
+
+import (
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// UploadSession tracks one in-progress resumable upload.
+type UploadSession struct {
+	gorm.Model
+	SessionID string `gorm:"uniqueIndex"`
+	PatientID string
+	Filename  string
+	TotalSize int64
+	Offset    int64
+	Complete  bool
+	ExpiresAt time.Time
+}
+
+type Store struct {
+	db *gorm.DB
+}
+
+func Open(dsn string) (*Store, error) {
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&UploadSession{}); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Create(session *UploadSession) error {
+	return s.db.Create(session).Error
+}
+
+func (s *Store) Get(sessionID string) (*UploadSession, error) {
+	var session UploadSession
+	if err := s.db.Where("session_id = ?", sessionID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *Store) UpdateOffset(sessionID string, offset int64) error {
+	return s.db.Model(&UploadSession{}).Where("session_id = ?", sessionID).Update("offset", offset).Error
+}
+
+func (s *Store) MarkComplete(sessionID string) error {
+	return s.db.Model(&UploadSession{}).Where("session_id = ?", sessionID).Update("complete", true).Error
+}
+
+// Delete hard-deletes the session row: this table is working metadata for an
+// in-progress upload, not an audit log, so there's nothing worth a soft
+// delete once a session is aborted or reaped.
+func (s *Store) Delete(sessionID string) error {
+	return s.db.Unscoped().Where("session_id = ?", sessionID).Delete(&UploadSession{}).Error
+}
+
+// ListExpired returns incomplete sessions whose ExpiresAt, and last update,
+// both fall before cutoff. Requiring updated_at too means a session still
+// receiving PATCHes (UpdateOffset bumps it) is never reaped out from under
+// an in-flight request just because it was long enough ago that the upload
+// started.
+func (s *Store) ListExpired(cutoff time.Time) ([]*UploadSession, error) {
+	var sessions []*UploadSession
+	if err := s.db.Where("complete = ? AND expires_at < ? AND updated_at < ?", false, cutoff, cutoff).Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}